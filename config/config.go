@@ -15,6 +15,12 @@ type Env struct {
 	APP_ENV              string `mapstructure:"APP_ENV"`
 	GOOGLE_CLIENT_ID     string `mapstructure:"GOOGLE_CLIENT_ID"`
 	GOOGLE_CLIENT_SECRET string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GITHUB_CLIENT_ID     string `mapstructure:"GITHUB_CLIENT_ID"`
+	GITHUB_CLIENT_SECRET string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	LARK_APP_ID          string `mapstructure:"LARK_APP_ID"`
+	LARK_APP_SECRET      string `mapstructure:"LARK_APP_SECRET"`
+	JWT_SECRET           string `mapstructure:"JWT_SECRET"`
+	JWT_EXPIRED_SECOND   int    `mapstructure:"JWT_EXPIRED_SECOND"`
 }
 
 func Load() *Env {
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthController handles local registration/login and JWT issuance.
+type AuthController struct {
+	auth *services.AuthService
+}
+
+func NewAuthController(auth *services.AuthService) *AuthController {
+	return &AuthController{auth: auth}
+}
+
+func (ctrl *AuthController) Register(c *fiber.Ctx) error {
+	var user models.User
+	if err := c.BodyParser(&user); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := ctrl.auth.Register(&user); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (ctrl *AuthController) Login(c *fiber.Ctx) error {
+	type request struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	var body request
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, token, err := ctrl.auth.Login(body.Email, body.Password)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Invalid credentials",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"token": token,
+		"data":  user,
+	})
+}
+
+func (ctrl *AuthController) Refresh(c *fiber.Ctx) error {
+	user := c.Locals("user").(*models.User)
+
+	token, err := ctrl.auth.GenerateToken(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to generate token",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"token": token,
+	})
+}
+
+// RequireAuth parses the Authorization: Bearer header, validates the JWT and
+// stashes the matching user under c.Locals("user").
+func (ctrl *AuthController) RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "Missing bearer token",
+			})
+		}
+
+		user, err := ctrl.auth.Authenticate(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "Invalid or expired token",
+			})
+		}
+
+		c.Locals("user", user)
+		return c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// MFAController drives the challenge/factor login flow.
+type MFAController struct {
+	mfa   *services.MFAService
+	store *session.Store
+}
+
+func NewMFAController(mfa *services.MFAService, store *session.Store) *MFAController {
+	return &MFAController{mfa: mfa, store: store}
+}
+
+func (ctrl *MFAController) Start(c *fiber.Ctx) error {
+	type request struct {
+		Email string `json:"email"`
+	}
+	var body request
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	challenge, factors, err := ctrl.mfa.StartChallenge(body.Email, c.IP(), string(c.Request().Header.UserAgent()))
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Invalid credentials",
+		})
+	}
+
+	factorList := make([]fiber.Map, 0, len(factors))
+	for _, f := range factors {
+		factorList = append(factorList, fiber.Map{
+			"id":   f.ID,
+			"type": f.Type,
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"challenge_id": challenge.ID,
+		"factors":      factorList,
+	})
+}
+
+func (ctrl *MFAController) Verify(c *fiber.Ctx) error {
+	type request struct {
+		ChallengeID int    `json:"challenge_id"`
+		FactorID    int    `json:"factor_id"`
+		Secret      string `json:"secret"`
+	}
+	var body request
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := ctrl.mfa.VerifyChallenge(body.ChallengeID, body.FactorID, body.Secret, c.IP(), string(c.Request().Header.UserAgent()))
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if result.User == nil {
+		return c.Status(200).JSON(fiber.Map{
+			"satisfied_factors": result.SatisfiedFactors,
+			"required_factors":  result.RequiredFactors,
+		})
+	}
+
+	sess, err := ctrl.store.Get(c)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get session",
+		})
+	}
+	sess.Set("user_id", result.User.ID)
+	if err := sess.Save(); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to save session",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Challenge satisfied",
+		"data":    result.User,
+	})
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// OAuthController drives the /auth/:provider/* routes backing login, the
+// OAuth callback and linking an extra provider to the signed-in user.
+type OAuthController struct {
+	oauth *services.OAuthService
+	auth  *services.AuthService
+	users *services.UserService
+	store *session.Store
+}
+
+func NewOAuthController(oauth *services.OAuthService, auth *services.AuthService, users *services.UserService, store *session.Store) *OAuthController {
+	return &OAuthController{oauth: oauth, auth: auth, users: users, store: store}
+}
+
+func (ctrl *OAuthController) Login(c *fiber.Ctx) error {
+	provider, ok := ctrl.oauth.Provider(c.Params("provider"))
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Unknown provider",
+		})
+	}
+
+	state, err := services.RandomState()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to generate state",
+		})
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     "oauthstate",
+		Value:    state,
+		Expires:  time.Now().Add(10 * time.Minute),
+		HTTPOnly: true,
+	})
+
+	return c.Redirect(provider.AuthCodeURL(state))
+}
+
+func (ctrl *OAuthController) Callback(c *fiber.Ctx) error {
+	state := c.Query("state")
+	if state == "" || state != c.Cookies("oauthstate") {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Invalid OAuth state",
+		})
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Missing code",
+		})
+	}
+
+	user, err := ctrl.oauth.Login(c.Params("provider"), code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to resolve user",
+		})
+	}
+
+	sess, err := ctrl.store.Get(c)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get session",
+		})
+	}
+	sess.Set("user_id", user.ID)
+	if err := sess.Save(); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to save session",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (ctrl *OAuthController) Link(c *fiber.Ctx) error {
+	user, err := ctrl.currentUser(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	type request struct {
+		Code string `json:"code"`
+	}
+	var body request
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	identity, err := ctrl.oauth.Link(c.Params("provider"), user, body.Code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to link provider",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"data": identity,
+	})
+}
+
+// currentUser resolves the authenticated User from either a JWT bearer token
+// or the session cookie, mirroring the checks UserController.Protected
+// performs.
+func (ctrl *OAuthController) currentUser(c *fiber.Ctx) (*models.User, error) {
+	if authHeader := c.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		return ctrl.auth.Authenticate(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+
+	sess, err := ctrl.store.Get(c)
+	if err != nil {
+		return nil, err
+	}
+	userID := sess.Get("user_id")
+	if userID == nil {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	return ctrl.users.FindByID(userID.(int))
+}
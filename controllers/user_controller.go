@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/DioSyahrizal/tylf-backend/pkg/cache"
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// UserController serves the landing page, the login page, the protected
+// demo route and logout.
+type UserController struct {
+	users          *services.UserService
+	auth           *services.AuthService
+	store          *session.Store
+	cache          *cache.Cache
+	googleClientID string
+}
+
+func NewUserController(users *services.UserService, auth *services.AuthService, store *session.Store, cache *cache.Cache, googleClientID string) *UserController {
+	return &UserController{users: users, auth: auth, store: store, cache: cache, googleClientID: googleClientID}
+}
+
+func (ctrl *UserController) Index(c *fiber.Ctx) error {
+	users, err := ctrl.users.List()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to fetch user",
+		})
+	}
+	return c.Status(200).JSON(users)
+}
+
+func (ctrl *UserController) LoginPage(c *fiber.Ctx) error {
+	sess, err := ctrl.store.Get(c)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get user ID from store",
+		})
+	}
+
+	if sess.Get("user_id") != nil {
+		return c.Redirect("/protected")
+	}
+
+	return c.Render("login", fiber.Map{
+		"Title":          "Hello, World!",
+		"GoogleClientId": ctrl.googleClientID,
+	})
+}
+
+func (ctrl *UserController) Protected(c *fiber.Ctx) error {
+	if authHeader := c.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		user, err := ctrl.auth.Authenticate(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "Unauthorized",
+			})
+		}
+		return c.Status(200).JSON(fiber.Map{
+			"message": "Welcome to the protected route!",
+			"user_id": user.ID,
+		})
+	}
+
+	// The session store is always consulted: it's the only place a revoked or
+	// store-expired session is caught. A cache hit on session:<id> cannot be
+	// trusted on its own here, because that cache entry outlives the session
+	// it mirrors whenever the session is invalidated some way other than this
+	// app's own Logout (store TTL, an admin deleting the row directly).
+	sess, err := ctrl.store.Get(c)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get session",
+		})
+	}
+
+	userID := sess.Get("user_id")
+	if userID == nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	// FindByID goes through the cached repository, so a warm user:<id> entry
+	// still saves the Postgres round trip even though the session itself is
+	// re-validated against the store on every request.
+	_, _ = ctrl.users.FindByID(userID.(int))
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Welcome to the protected route!",
+		"user_id": userID,
+	})
+}
+
+func (ctrl *UserController) Logout(c *fiber.Ctx) error {
+	sess, err := ctrl.store.Get(c)
+	userID := sess.Get("user_id")
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get user ID from store",
+		})
+	}
+	if userID == nil {
+		return c.Status(401).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	sess.Delete("user_id")
+
+	if err := sess.Save(); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to save session",
+		})
+	}
+
+	_ = ctrl.cache.InvalidateUser(userID.(int))
+
+	return c.Status(200).JSON(fiber.Map{
+		"message": "Logged out successfully",
+	})
+}
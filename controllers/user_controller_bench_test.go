@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/pkg/cache"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// newBenchApp is like newTestApp but also returns the controller so
+// benchmarks can reach into its cache between iterations.
+func newBenchApp(b *testing.B) (*fiber.App, *UserController) {
+	b.Helper()
+
+	repo := &fakeUserRepository{byID: map[int]*models.User{
+		1: {ID: 1, Name: "Jane Doe", Email: "jane@example.com"},
+	}}
+
+	userCache, err := cache.Open(filepath.Join(b.TempDir(), "cache.db"))
+	if err != nil {
+		b.Fatalf("cache.Open() error = %v", err)
+	}
+	b.Cleanup(func() { _ = userCache.Close() })
+
+	cachedRepo := repository.NewCachedUserRepository(repo, userCache)
+	userService := services.NewUserService(cachedRepo)
+	authService := services.NewAuthService(cachedRepo, "test-secret", 3600)
+	store := session.New()
+
+	ctrl := NewUserController(userService, authService, store, userCache, "")
+
+	app := fiber.New()
+	app.Get("/set", func(c *fiber.Ctx) error {
+		sess, err := store.Get(c)
+		if err != nil {
+			return err
+		}
+		sess.Set("user_id", 1)
+		return sess.Save()
+	})
+	app.Get("/protected", ctrl.Protected)
+	return app, ctrl
+}
+
+func protectedRequest(b *testing.B, app *fiber.App, cookies []*http.Cookie) {
+	b.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	for _, ck := range cookies {
+		req.AddCookie(ck)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		b.Fatalf("app.Test(/protected) error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		b.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+}
+
+// BenchmarkProtected_WithCache hits /protected repeatedly on the same
+// session, so after the first request the user is served from cache.
+func BenchmarkProtected_WithCache(b *testing.B) {
+	app, _ := newBenchApp(b)
+	cookies := loginCookies(b, app)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		protectedRequest(b, app, cookies)
+	}
+}
+
+// BenchmarkProtected_WithoutCache invalidates the cached user before every
+// request, forcing each one through the Postgres-backed session store and
+// user repository, to show the savings BenchmarkProtected_WithCache gets.
+func BenchmarkProtected_WithoutCache(b *testing.B) {
+	app, ctrl := newBenchApp(b)
+	cookies := loginCookies(b, app)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctrl.cache.InvalidateUser(1)
+		protectedRequest(b, app, cookies)
+	}
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/pkg/cache"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+	"github.com/DioSyahrizal/tylf-backend/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// fakeUserRepository is an in-memory repository.UserRepository for exercising
+// UserController without a database.
+type fakeUserRepository struct {
+	byID map[int]*models.User
+}
+
+func (f *fakeUserRepository) List() ([]models.User, error) { return nil, nil }
+
+func (f *fakeUserRepository) FindByID(id int) (*models.User, error) {
+	user, ok := f.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+	return user, nil
+}
+
+func (f *fakeUserRepository) FindByEmail(string) (*models.User, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeUserRepository) Create(*models.User) error { return nil }
+
+func (f *fakeUserRepository) Update(*models.User) error { return nil }
+
+// newTestApp wires a real UserController (session store + bbolt cache) behind
+// a minimal fiber app with a /set route to stand in for a completed login.
+// It takes testing.TB so benchmarks can share it with tests.
+func newTestApp(t testing.TB) *fiber.App {
+	t.Helper()
+
+	repo := &fakeUserRepository{byID: map[int]*models.User{
+		1: {ID: 1, Name: "Jane Doe", Email: "jane@example.com"},
+	}}
+
+	userCache, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("cache.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = userCache.Close() })
+
+	cachedRepo := repository.NewCachedUserRepository(repo, userCache)
+	userService := services.NewUserService(cachedRepo)
+	authService := services.NewAuthService(cachedRepo, "test-secret", 3600)
+	store := session.New()
+
+	ctrl := NewUserController(userService, authService, store, userCache, "")
+
+	app := fiber.New()
+	app.Get("/set", func(c *fiber.Ctx) error {
+		sess, err := store.Get(c)
+		if err != nil {
+			return err
+		}
+		sess.Set("user_id", 1)
+		return sess.Save()
+	})
+	app.Get("/protected", ctrl.Protected)
+	app.Get("/logout", ctrl.Logout)
+	return app
+}
+
+func loginCookies(t testing.TB, app *fiber.App) []*http.Cookie {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/set", nil))
+	if err != nil {
+		t.Fatalf("app.Test(/set) error = %v", err)
+	}
+	return resp.Cookies()
+}
+
+func TestProtected_RejectsAnonymousRequest(t *testing.T) {
+	app := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/protected", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+}
+
+func TestProtected_AllowsSessionThenCachedRequest(t *testing.T) {
+	app := newTestApp(t)
+	cookies := loginCookies(t, app)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		for _, ck := range cookies {
+			req.AddCookie(ck)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test() call %d error = %v", i, err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("call %d status = %d, want %d", i, resp.StatusCode, fiber.StatusOK)
+		}
+	}
+}
+
+func TestLogout_InvalidatesTheSession(t *testing.T) {
+	app := newTestApp(t)
+	cookies := loginCookies(t, app)
+
+	logoutReq := httptest.NewRequest(http.MethodGet, "/logout", nil)
+	for _, ck := range cookies {
+		logoutReq.AddCookie(ck)
+	}
+	logoutResp, err := app.Test(logoutReq)
+	if err != nil {
+		t.Fatalf("app.Test(/logout) error = %v", err)
+	}
+	if logoutResp.StatusCode != fiber.StatusOK {
+		t.Fatalf("logout status = %d, want %d", logoutResp.StatusCode, fiber.StatusOK)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	for _, ck := range cookies {
+		req.AddCookie(ck)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test(/protected) error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("status after logout = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+}
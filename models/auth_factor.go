@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FactorType identifies the kind of credential an AuthFactor enforces.
+type FactorType string
+
+const (
+	FactorTOTP       FactorType = "totp"
+	FactorEmailOTP   FactorType = "email_otp"
+	FactorBackupCode FactorType = "backup_code"
+	FactorPassword   FactorType = "password"
+)
+
+// ChallengeTTL is how long a challenge stays valid before it must be restarted.
+const ChallengeTTL = 10 * time.Minute
+
+// AuthFactor is a credential enrolled by a user that a Challenge must satisfy
+// before a session is issued.
+type AuthFactor struct {
+	ID        int            `json:"id" gorm:"primaryKey"`
+	UserID    int            `json:"user_id" gorm:"not null;index"`
+	Type      FactorType     `json:"type" gorm:"not null"`
+	Secret    string         `json:"-" gorm:"not null,column:secret"`
+	Enabled   bool           `json:"enabled" gorm:"not null;default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"nullable;index"`
+}
+
+// Challenge tracks a single login attempt across one or more factor checks.
+// It is pinned to the client IP/User-Agent that started it so a verify call
+// can't be replayed from a different client.
+type Challenge struct {
+	ID               int       `json:"id" gorm:"primaryKey"`
+	UserID           int       `json:"user_id" gorm:"not null;index"`
+	IP               string    `json:"-" gorm:"not null"`
+	UserAgent        string    `json:"-" gorm:"not null"`
+	RequiredFactors  int       `json:"required_factors" gorm:"not null"`
+	SatisfiedFactors int       `json:"satisfied_factors" gorm:"not null;default:0"`
+	VerifiedFactors  string    `json:"-" gorm:"not null;default:''"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Expired reports whether the challenge is older than ChallengeTTL.
+func (ch Challenge) Expired() bool {
+	return time.Since(ch.CreatedAt) > ChallengeTTL
+}
+
+// FingerprintMatches reports whether the given IP/User-Agent match the pair
+// the challenge was started with.
+func (ch Challenge) FingerprintMatches(ip, userAgent string) bool {
+	return ch.IP == ip && ch.UserAgent == userAgent
+}
+
+// HasVerified reports whether factorID already counted towards this
+// challenge, so the same factor can't be replayed to pad the count.
+func (ch Challenge) HasVerified(factorID int) bool {
+	for _, id := range strings.Split(ch.VerifiedFactors, ",") {
+		if id == strconv.Itoa(factorID) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkVerified records factorID as having satisfied one required factor.
+func (ch *Challenge) MarkVerified(factorID int) {
+	if ch.HasVerified(factorID) {
+		return
+	}
+	if ch.VerifiedFactors == "" {
+		ch.VerifiedFactors = strconv.Itoa(factorID)
+	} else {
+		ch.VerifiedFactors += "," + strconv.Itoa(factorID)
+	}
+	ch.SatisfiedFactors++
+}
+
+// Satisfied reports whether enough factors have been verified to issue a
+// session.
+func (ch Challenge) Satisfied() bool {
+	return ch.SatisfiedFactors >= ch.RequiredFactors
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OAuthIdentity links a User to an account on an external provider, so one
+// User can have several linked providers.
+type OAuthIdentity struct {
+	ID             int            `json:"id" gorm:"primaryKey"`
+	Provider       string         `json:"provider" gorm:"not null;uniqueIndex:idx_oauth_identity_provider_user"`
+	ProviderUserID string         `json:"provider_user_id" gorm:"not null;uniqueIndex:idx_oauth_identity_provider_user;column:provider_user_id"`
+	UserID         int            `json:"user_id" gorm:"not null;index"`
+	Info           datatypes.JSON `json:"info"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func (OAuthIdentity) TableName() string {
+	return "oauth_identities"
+}
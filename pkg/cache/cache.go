@@ -0,0 +1,91 @@
+// Package cache is a small embedded, on-disk cache backed by bbolt. It is
+// meant to sit in front of the user-by-ID lookup that's hit on every
+// authenticated request, so most requests skip the Postgres round trip.
+// Session validity itself is never cached here: it must stay authoritative
+// against the session store so expiry and revocation take effect immediately.
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("cache")
+
+// Cache is a TTL key/value store on top of a single bbolt bucket.
+type Cache struct {
+	db *bolt.DB
+}
+
+type entry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Open creates/opens the bbolt database at path and ensures the cache bucket
+// exists.
+func Open(path string) (*Cache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Cache{db: db}, nil
+}
+
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Set stores value under key for ttl.
+func (c *Cache) Set(key string, value []byte, ttl time.Duration) error {
+	data, err := json.Marshal(entry{Value: value, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), data)
+	})
+}
+
+// Get returns the value stored under key, or ok=false if it's missing or
+// expired. An expired entry is lazily deleted.
+func (c *Cache) Get(key string) (value []byte, ok bool) {
+	var raw []byte
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketName).Get([]byte(key)); v != nil {
+			raw = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if raw == nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false
+	}
+	if time.Now().After(e.ExpiresAt) {
+		_ = c.Delete(key)
+		return nil, false
+	}
+	return e.Value, true
+}
+
+// Delete removes key, if present.
+func (c *Cache) Delete(key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+}
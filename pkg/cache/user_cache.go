@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+)
+
+const userTTL = 5 * time.Minute
+
+func userKey(id int) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// CachedUserByID returns the cached User for id, if present and unexpired.
+func (c *Cache) CachedUserByID(id int) (*models.User, bool) {
+	data, ok := c.Get(userKey(id))
+	if !ok {
+		return nil, false
+	}
+
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, false
+	}
+	return &user, true
+}
+
+// SetUser caches user under its ID.
+func (c *Cache) SetUser(user *models.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	return c.Set(userKey(user.ID), data, userTTL)
+}
+
+// InvalidateUser removes a cached user, e.g. after an update or logout.
+func (c *Cache) InvalidateUser(id int) error {
+	return c.Delete(userKey(id))
+}
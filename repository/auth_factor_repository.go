@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"gorm.io/gorm"
+)
+
+// AuthFactorRepository is the data-access interface for AuthFactor.
+type AuthFactorRepository interface {
+	ListEnabledByUserID(userID int) ([]models.AuthFactor, error)
+	FindEnabledByIDAndUserID(id, userID int) (*models.AuthFactor, error)
+}
+
+type authFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthFactorRepository(db *gorm.DB) AuthFactorRepository {
+	return &authFactorRepository{db: db}
+}
+
+func (r *authFactorRepository) ListEnabledByUserID(userID int) ([]models.AuthFactor, error) {
+	var factors []models.AuthFactor
+	err := r.db.Where("user_id = ? AND enabled = ?", userID, true).Find(&factors).Error
+	return factors, err
+}
+
+func (r *authFactorRepository) FindEnabledByIDAndUserID(id, userID int) (*models.AuthFactor, error) {
+	var factor models.AuthFactor
+	if err := r.db.Where("id = ? AND user_id = ? AND enabled = ?", id, userID, true).First(&factor).Error; err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
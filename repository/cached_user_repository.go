@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/pkg/cache"
+)
+
+// cachedUserRepository wraps a UserRepository with a bbolt-backed cache so
+// repeat lookups by ID (the hot path for JWT/session validation) skip
+// Postgres entirely. List/FindByEmail pass straight through since they aren't
+// keyed on something the cache indexes.
+type cachedUserRepository struct {
+	inner UserRepository
+	cache *cache.Cache
+}
+
+// NewCachedUserRepository decorates inner with cache.
+func NewCachedUserRepository(inner UserRepository, cache *cache.Cache) UserRepository {
+	return &cachedUserRepository{inner: inner, cache: cache}
+}
+
+func (r *cachedUserRepository) List() ([]models.User, error) {
+	return r.inner.List()
+}
+
+func (r *cachedUserRepository) FindByID(id int) (*models.User, error) {
+	if user, ok := r.cache.CachedUserByID(id); ok {
+		return user, nil
+	}
+
+	user, err := r.inner.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.cache.SetUser(user)
+	return user, nil
+}
+
+func (r *cachedUserRepository) FindByEmail(email string) (*models.User, error) {
+	return r.inner.FindByEmail(email)
+}
+
+func (r *cachedUserRepository) Create(user *models.User) error {
+	return r.inner.Create(user)
+}
+
+func (r *cachedUserRepository) Update(user *models.User) error {
+	if err := r.inner.Update(user); err != nil {
+		return err
+	}
+	return r.cache.InvalidateUser(user.ID)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/pkg/cache"
+)
+
+// countingUserRepository wraps a UserRepository and counts FindByID calls so
+// tests can assert the cache actually short-circuits Postgres.
+type countingUserRepository struct {
+	UserRepository
+	findByIDCalls int
+}
+
+func (r *countingUserRepository) FindByID(id int) (*models.User, error) {
+	r.findByIDCalls++
+	return r.UserRepository.FindByID(id)
+}
+
+type fakeUserRepository struct {
+	byID map[int]*models.User
+}
+
+func (f *fakeUserRepository) List() ([]models.User, error) { return nil, nil }
+
+func (f *fakeUserRepository) FindByID(id int) (*models.User, error) {
+	user, ok := f.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+	return user, nil
+}
+
+func (f *fakeUserRepository) FindByEmail(string) (*models.User, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeUserRepository) Create(*models.User) error { return nil }
+
+func (f *fakeUserRepository) Update(*models.User) error { return nil }
+
+func newTestCache(t *testing.T) *cache.Cache {
+	t.Helper()
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("cache.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestCachedUserRepository_FindByIDCachesAfterFirstLookup(t *testing.T) {
+	inner := &countingUserRepository{UserRepository: &fakeUserRepository{byID: map[int]*models.User{
+		1: {ID: 1, Name: "Jane Doe", Email: "jane@example.com"},
+	}}}
+	repo := NewCachedUserRepository(inner, newTestCache(t))
+
+	if _, err := repo.FindByID(1); err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if _, err := repo.FindByID(1); err != nil {
+		t.Fatalf("FindByID() second call error = %v", err)
+	}
+
+	if inner.findByIDCalls != 1 {
+		t.Fatalf("inner.FindByID called %d times, want 1", inner.findByIDCalls)
+	}
+}
+
+func TestCachedUserRepository_UpdateInvalidatesCache(t *testing.T) {
+	user := &models.User{ID: 1, Name: "Jane Doe", Email: "jane@example.com"}
+	inner := &countingUserRepository{UserRepository: &fakeUserRepository{byID: map[int]*models.User{1: user}}}
+	repo := NewCachedUserRepository(inner, newTestCache(t))
+
+	if _, err := repo.FindByID(1); err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if err := repo.Update(user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := repo.FindByID(1); err != nil {
+		t.Fatalf("FindByID() after update error = %v", err)
+	}
+
+	if inner.findByIDCalls != 2 {
+		t.Fatalf("inner.FindByID called %d times after an update invalidated the cache, want 2", inner.findByIDCalls)
+	}
+}
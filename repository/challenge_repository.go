@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"gorm.io/gorm"
+)
+
+// ChallengeRepository is the data-access interface for Challenge.
+type ChallengeRepository interface {
+	Create(challenge *models.Challenge) error
+	FindByID(id int) (*models.Challenge, error)
+	Save(challenge *models.Challenge) error
+}
+
+type challengeRepository struct {
+	db *gorm.DB
+}
+
+func NewChallengeRepository(db *gorm.DB) ChallengeRepository {
+	return &challengeRepository{db: db}
+}
+
+func (r *challengeRepository) Create(challenge *models.Challenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *challengeRepository) FindByID(id int) (*models.Challenge, error) {
+	var challenge models.Challenge
+	if err := r.db.First(&challenge, id).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *challengeRepository) Save(challenge *models.Challenge) error {
+	return r.db.Save(challenge).Error
+}
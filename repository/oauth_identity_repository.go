@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"gorm.io/gorm"
+)
+
+// OAuthIdentityRepository is the data-access interface for OAuthIdentity.
+type OAuthIdentityRepository interface {
+	FindByProviderAndProviderUserID(provider, providerUserID string) (*models.OAuthIdentity, error)
+	Create(identity *models.OAuthIdentity) error
+}
+
+type oauthIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthIdentityRepository(db *gorm.DB) OAuthIdentityRepository {
+	return &oauthIdentityRepository{db: db}
+}
+
+func (r *oauthIdentityRepository) FindByProviderAndProviderUserID(provider, providerUserID string) (*models.OAuthIdentity, error) {
+	var identity models.OAuthIdentity
+	if err := r.db.Where("provider = ? AND provider_user_id = ?", provider, providerUserID).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *oauthIdentityRepository) Create(identity *models.OAuthIdentity) error {
+	return r.db.Create(identity).Error
+}
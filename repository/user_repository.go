@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"gorm.io/gorm"
+)
+
+// UserRepository is the data-access interface for User, so services can be
+// tested against a fake instead of a real database.
+type UserRepository interface {
+	List() ([]models.User, error)
+	FindByID(id int) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	Create(user *models.User) error
+	Update(user *models.User) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) List() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) FindByID(id int) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepository) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
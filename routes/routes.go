@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/controllers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controllers bundles every controller routes.Register wires up, so main.go
+// only has to build each one once and hand them off here.
+type Controllers struct {
+	User  *controllers.UserController
+	Auth  *controllers.AuthController
+	MFA   *controllers.MFAController
+	OAuth *controllers.OAuthController
+}
+
+// Register mounts every route on app.
+func Register(app *fiber.App, c Controllers) {
+	app.Get("/", c.User.Index)
+	app.Get("/login", c.User.LoginPage)
+	app.Get("/protected", c.User.Protected)
+	app.Get("/logout", c.User.Logout)
+
+	app.Get("/auth/:provider/login", c.OAuth.Login)
+	app.Get("/auth/:provider/callback", c.OAuth.Callback)
+	app.Post("/auth/link/:provider", c.OAuth.Link)
+
+	app.Post("/auth/register", c.Auth.Register)
+	app.Post("/auth/login", c.Auth.Login)
+	app.Post("/auth/refresh", c.Auth.RequireAuth(), c.Auth.Refresh)
+
+	app.Post("/auth/challenge/start", c.MFA.Start)
+	app.Post("/auth/challenge/verify", c.MFA.Verify)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// validate enforces the `validate` struct tags on incoming request bodies.
+var validate = validator.New()
+
+// userClaims are the JWT claims issued on login/refresh.
+type userClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// AuthService handles registration, local login and JWT issuance/validation.
+type AuthService struct {
+	users             repository.UserRepository
+	jwtSecret         string
+	jwtExpiredSeconds int
+}
+
+func NewAuthService(users repository.UserRepository, jwtSecret string, jwtExpiredSeconds int) *AuthService {
+	return &AuthService{
+		users:             users,
+		jwtSecret:         jwtSecret,
+		jwtExpiredSeconds: jwtExpiredSeconds,
+	}
+}
+
+// Register validates and creates a new user, bcrypt-hashing the password.
+func (s *AuthService) Register(user *models.User) error {
+	if err := validate.Struct(user); err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+
+	return s.users.Create(user)
+}
+
+// Login verifies credentials and returns the user with a signed JWT.
+func (s *AuthService) Login(email, password string) (*models.User, string, error) {
+	user, err := s.users.FindByEmail(email)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid credentials")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, "", fmt.Errorf("invalid credentials")
+	}
+
+	token, err := s.GenerateToken(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// GenerateToken signs a JWT for userID, valid for jwtExpiredSeconds.
+func (s *AuthService) GenerateToken(userID int) (string, error) {
+	claims := userClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.jwtExpiredSeconds) * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.jwtSecret))
+}
+
+// Authenticate validates a bearer token and loads the user it identifies.
+// It backs RequireAuth and any route that accepts a JWT.
+func (s *AuthService) Authenticate(tokenString string) (*models.User, error) {
+	claims := &userClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(s.jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return s.users.FindByID(claims.UserID)
+}
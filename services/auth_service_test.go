@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+)
+
+func TestAuthService_RegisterLoginAuthenticate(t *testing.T) {
+	repo := newFakeUserRepository()
+	svc := NewAuthService(repo, "test-secret", 3600)
+
+	user := &models.User{
+		Name:     "Jane Doe",
+		Email:    "jane@example.com",
+		Password: "password123",
+		Phone:    81234567890,
+	}
+	if err := svc.Register(user); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if user.Password == "password123" {
+		t.Fatal("Register() left the password unhashed")
+	}
+
+	loggedIn, token, err := svc.Login("jane@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if loggedIn.ID != user.ID {
+		t.Fatalf("Login() user ID = %d, want %d", loggedIn.ID, user.ID)
+	}
+	if token == "" {
+		t.Fatal("Login() returned an empty token")
+	}
+
+	authed, err := svc.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if authed.ID != user.ID {
+		t.Fatalf("Authenticate() user ID = %d, want %d", authed.ID, user.ID)
+	}
+}
+
+func TestAuthService_LoginWrongPassword(t *testing.T) {
+	repo := newFakeUserRepository()
+	svc := NewAuthService(repo, "test-secret", 3600)
+
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "password123", Phone: 81234567890}
+	if err := svc.Register(user); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if _, _, err := svc.Login("jane@example.com", "wrong-password"); err == nil {
+		t.Fatal("Login() with the wrong password succeeded")
+	}
+}
+
+func TestAuthService_RegisterRejectsInvalidInput(t *testing.T) {
+	repo := newFakeUserRepository()
+	svc := NewAuthService(repo, "test-secret", 3600)
+
+	user := &models.User{Name: "Jo", Email: "not-an-email", Password: "short", Phone: 1}
+	if err := svc.Register(user); err == nil {
+		t.Fatal("Register() with invalid input succeeded")
+	}
+}
+
+func TestAuthService_AuthenticateRejectsGarbageToken(t *testing.T) {
+	repo := newFakeUserRepository()
+	svc := NewAuthService(repo, "test-secret", 3600)
+
+	if _, err := svc.Authenticate("not-a-jwt"); err == nil {
+		t.Fatal("Authenticate() with a garbage token succeeded")
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+)
+
+// fakeUserRepository is an in-memory repository.UserRepository used to unit
+// test services without a database.
+type fakeUserRepository struct {
+	byID    map[int]*models.User
+	byEmail map[string]*models.User
+	nextID  int
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{
+		byID:    map[int]*models.User{},
+		byEmail: map[string]*models.User{},
+		nextID:  1,
+	}
+}
+
+func (f *fakeUserRepository) List() ([]models.User, error) {
+	users := make([]models.User, 0, len(f.byID))
+	for _, u := range f.byID {
+		users = append(users, *u)
+	}
+	return users, nil
+}
+
+func (f *fakeUserRepository) FindByID(id int) (*models.User, error) {
+	user, ok := f.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+	cp := *user
+	return &cp, nil
+}
+
+func (f *fakeUserRepository) FindByEmail(email string) (*models.User, error) {
+	user, ok := f.byEmail[email]
+	if !ok {
+		return nil, fmt.Errorf("user with email %q not found", email)
+	}
+	cp := *user
+	return &cp, nil
+}
+
+func (f *fakeUserRepository) Create(user *models.User) error {
+	user.ID = f.nextID
+	f.nextID++
+	cp := *user
+	f.byID[user.ID] = &cp
+	f.byEmail[user.Email] = &cp
+	return nil
+}
+
+func (f *fakeUserRepository) Update(user *models.User) error {
+	cp := *user
+	f.byID[user.ID] = &cp
+	f.byEmail[user.Email] = &cp
+	return nil
+}
+
+// fakeAuthFactorRepository is an in-memory repository.AuthFactorRepository.
+type fakeAuthFactorRepository struct {
+	byUser map[int][]models.AuthFactor
+}
+
+func (f *fakeAuthFactorRepository) ListEnabledByUserID(userID int) ([]models.AuthFactor, error) {
+	var enabled []models.AuthFactor
+	for _, factor := range f.byUser[userID] {
+		if factor.Enabled {
+			enabled = append(enabled, factor)
+		}
+	}
+	return enabled, nil
+}
+
+func (f *fakeAuthFactorRepository) FindEnabledByIDAndUserID(id, userID int) (*models.AuthFactor, error) {
+	for _, factor := range f.byUser[userID] {
+		if factor.ID == id && factor.Enabled {
+			cp := factor
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("factor %d not found for user %d", id, userID)
+}
+
+// fakeChallengeRepository is an in-memory repository.ChallengeRepository.
+type fakeChallengeRepository struct {
+	byID   map[int]*models.Challenge
+	nextID int
+}
+
+func newFakeChallengeRepository() *fakeChallengeRepository {
+	return &fakeChallengeRepository{byID: map[int]*models.Challenge{}, nextID: 1}
+}
+
+func (f *fakeChallengeRepository) Create(challenge *models.Challenge) error {
+	challenge.ID = f.nextID
+	f.nextID++
+	cp := *challenge
+	f.byID[challenge.ID] = &cp
+	return nil
+}
+
+func (f *fakeChallengeRepository) FindByID(id int) (*models.Challenge, error) {
+	challenge, ok := f.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("challenge %d not found", id)
+	}
+	cp := *challenge
+	return &cp, nil
+}
+
+func (f *fakeChallengeRepository) Save(challenge *models.Challenge) error {
+	cp := *challenge
+	f.byID[challenge.ID] = &cp
+	return nil
+}
+
+// fakeOAuthIdentityRepository is an in-memory repository.OAuthIdentityRepository.
+type fakeOAuthIdentityRepository struct {
+	byKey  map[string]*models.OAuthIdentity
+	nextID int
+}
+
+func newFakeOAuthIdentityRepository() *fakeOAuthIdentityRepository {
+	return &fakeOAuthIdentityRepository{byKey: map[string]*models.OAuthIdentity{}, nextID: 1}
+}
+
+func oauthIdentityKey(provider, providerUserID string) string {
+	return provider + ":" + providerUserID
+}
+
+func (f *fakeOAuthIdentityRepository) FindByProviderAndProviderUserID(provider, providerUserID string) (*models.OAuthIdentity, error) {
+	identity, ok := f.byKey[oauthIdentityKey(provider, providerUserID)]
+	if !ok {
+		return nil, fmt.Errorf("identity not found")
+	}
+	cp := *identity
+	return &cp, nil
+}
+
+func (f *fakeOAuthIdentityRepository) Create(identity *models.OAuthIdentity) error {
+	identity.ID = f.nextID
+	f.nextID++
+	cp := *identity
+	f.byKey[oauthIdentityKey(identity.Provider, identity.ProviderUserID)] = &cp
+	return nil
+}
+
+// fakeOAuthProvider is a deterministic OAuthProvider for tests.
+type fakeOAuthProvider struct {
+	providerUserID string
+	name           string
+	email          string
+}
+
+func (p fakeOAuthProvider) AuthCodeURL(state string) string {
+	return "https://example.test/auth?state=" + state
+}
+
+func (p fakeOAuthProvider) Exchange(code string) (OAuthToken, error) {
+	return OAuthToken{AccessToken: "token-for-" + code}, nil
+}
+
+func (p fakeOAuthProvider) FetchUserInfo(_ OAuthToken) (ProviderUser, error) {
+	return ProviderUser{ID: p.providerUserID, Name: p.name, Email: p.email, Raw: []byte(`{}`)}, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MFAService drives the challenge/factor login flow.
+type MFAService struct {
+	users      repository.UserRepository
+	factors    repository.AuthFactorRepository
+	challenges repository.ChallengeRepository
+}
+
+func NewMFAService(users repository.UserRepository, factors repository.AuthFactorRepository, challenges repository.ChallengeRepository) *MFAService {
+	return &MFAService{users: users, factors: factors, challenges: challenges}
+}
+
+// StartChallenge looks up user by email and creates a Challenge pinned to the
+// given client fingerprint, requiring all of the user's enrolled factors.
+func (s *MFAService) StartChallenge(email, ip, userAgent string) (*models.Challenge, []models.AuthFactor, error) {
+	user, err := s.users.FindByEmail(email)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credentials")
+	}
+
+	factors, err := s.factors.ListEnabledByUserID(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(factors) == 0 {
+		return nil, nil, fmt.Errorf("no enrolled factors")
+	}
+
+	challenge := &models.Challenge{
+		UserID:          user.ID,
+		IP:              ip,
+		UserAgent:       userAgent,
+		RequiredFactors: len(factors),
+		CreatedAt:       time.Now(),
+	}
+	if err := s.challenges.Create(challenge); err != nil {
+		return nil, nil, err
+	}
+
+	return challenge, factors, nil
+}
+
+// ChallengeResult is the outcome of a verify call. User is only populated
+// once every required factor has been satisfied.
+type ChallengeResult struct {
+	SatisfiedFactors int
+	RequiredFactors  int
+	User             *models.User
+}
+
+// VerifyChallenge re-checks the fingerprint, validates secret against
+// factorID and advances the challenge. A session should only be issued once
+// Satisfied() is true.
+func (s *MFAService) VerifyChallenge(challengeID, factorID int, secret, ip, userAgent string) (*ChallengeResult, error) {
+	challenge, err := s.challenges.FindByID(challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge")
+	}
+
+	if challenge.Expired() {
+		return nil, fmt.Errorf("challenge expired")
+	}
+	if !challenge.FingerprintMatches(ip, userAgent) {
+		return nil, fmt.Errorf("challenge fingerprint mismatch")
+	}
+
+	factor, err := s.factors.FindEnabledByIDAndUserID(factorID, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid factor")
+	}
+
+	if !verifyFactorSecret(*factor, secret) {
+		return nil, fmt.Errorf("invalid secret")
+	}
+
+	if !challenge.HasVerified(factor.ID) {
+		challenge.MarkVerified(factor.ID)
+		if err := s.challenges.Save(challenge); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ChallengeResult{
+		SatisfiedFactors: challenge.SatisfiedFactors,
+		RequiredFactors:  challenge.RequiredFactors,
+	}
+	if !challenge.Satisfied() {
+		return result, nil
+	}
+
+	user, err := s.users.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result.User = user
+	return result, nil
+}
+
+// verifyFactorSecret validates secret against factor according to its type.
+// Password and hashed-code factors (email OTP, backup codes) are stored as
+// bcrypt hashes; TOTP factors store the base32 seed used by the authenticator
+// app.
+func verifyFactorSecret(factor models.AuthFactor, secret string) bool {
+	switch factor.Type {
+	case models.FactorPassword, models.FactorEmailOTP, models.FactorBackupCode:
+		return bcrypt.CompareHashAndPassword([]byte(factor.Secret), []byte(secret)) == nil
+	case models.FactorTOTP:
+		return totp.Validate(secret, factor.Secret)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMFAService_StartAndVerifyChallenge(t *testing.T) {
+	users := newFakeUserRepository()
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "x", Phone: 81234567890}
+	if err := users.Create(user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	factors := &fakeAuthFactorRepository{byUser: map[int][]models.AuthFactor{
+		user.ID: {{ID: 1, UserID: user.ID, Type: models.FactorPassword, Secret: string(hashed), Enabled: true}},
+	}}
+	challenges := newFakeChallengeRepository()
+
+	svc := NewMFAService(users, factors, challenges)
+
+	challenge, required, err := svc.StartChallenge(user.Email, "1.2.3.4", "test-agent")
+	if err != nil {
+		t.Fatalf("StartChallenge() error = %v", err)
+	}
+	if len(required) != 1 {
+		t.Fatalf("StartChallenge() required factors = %d, want 1", len(required))
+	}
+
+	result, err := svc.VerifyChallenge(challenge.ID, required[0].ID, "s3cret!", "1.2.3.4", "test-agent")
+	if err != nil {
+		t.Fatalf("VerifyChallenge() error = %v", err)
+	}
+	if result.User == nil || result.User.ID != user.ID {
+		t.Fatalf("VerifyChallenge() result.User = %v, want user %d", result.User, user.ID)
+	}
+}
+
+func TestMFAService_VerifyChallenge_WrongSecret(t *testing.T) {
+	users := newFakeUserRepository()
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "x", Phone: 81234567890}
+	_ = users.Create(user)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.DefaultCost)
+	factors := &fakeAuthFactorRepository{byUser: map[int][]models.AuthFactor{
+		user.ID: {{ID: 1, UserID: user.ID, Type: models.FactorPassword, Secret: string(hashed), Enabled: true}},
+	}}
+	challenges := newFakeChallengeRepository()
+	svc := NewMFAService(users, factors, challenges)
+
+	challenge, required, err := svc.StartChallenge(user.Email, "1.2.3.4", "test-agent")
+	if err != nil {
+		t.Fatalf("StartChallenge() error = %v", err)
+	}
+
+	if _, err := svc.VerifyChallenge(challenge.ID, required[0].ID, "wrong-secret", "1.2.3.4", "test-agent"); err == nil {
+		t.Fatal("VerifyChallenge() with the wrong secret succeeded")
+	}
+}
+
+func TestMFAService_VerifyChallenge_FingerprintMismatch(t *testing.T) {
+	users := newFakeUserRepository()
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "x", Phone: 81234567890}
+	_ = users.Create(user)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.DefaultCost)
+	factors := &fakeAuthFactorRepository{byUser: map[int][]models.AuthFactor{
+		user.ID: {{ID: 1, UserID: user.ID, Type: models.FactorPassword, Secret: string(hashed), Enabled: true}},
+	}}
+	challenges := newFakeChallengeRepository()
+	svc := NewMFAService(users, factors, challenges)
+
+	challenge, required, err := svc.StartChallenge(user.Email, "1.2.3.4", "test-agent")
+	if err != nil {
+		t.Fatalf("StartChallenge() error = %v", err)
+	}
+
+	if _, err := svc.VerifyChallenge(challenge.ID, required[0].ID, "s3cret!", "9.9.9.9", "test-agent"); err == nil {
+		t.Fatal("VerifyChallenge() from a different IP succeeded")
+	}
+}
+
+func TestMFAService_VerifyChallenge_Expired(t *testing.T) {
+	users := newFakeUserRepository()
+	user := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "x", Phone: 81234567890}
+	_ = users.Create(user)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("s3cret!"), bcrypt.DefaultCost)
+	factors := &fakeAuthFactorRepository{byUser: map[int][]models.AuthFactor{
+		user.ID: {{ID: 1, UserID: user.ID, Type: models.FactorPassword, Secret: string(hashed), Enabled: true}},
+	}}
+	challenges := newFakeChallengeRepository()
+	svc := NewMFAService(users, factors, challenges)
+
+	challenge, required, err := svc.StartChallenge(user.Email, "1.2.3.4", "test-agent")
+	if err != nil {
+		t.Fatalf("StartChallenge() error = %v", err)
+	}
+
+	stored, _ := challenges.FindByID(challenge.ID)
+	stored.CreatedAt = time.Now().Add(-models.ChallengeTTL - time.Minute)
+	_ = challenges.Save(stored)
+
+	if _, err := svc.VerifyChallenge(challenge.ID, required[0].ID, "s3cret!", "1.2.3.4", "test-agent"); err == nil {
+		t.Fatal("VerifyChallenge() on an expired challenge succeeded")
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DioSyahrizal/tylf-backend/config"
+)
+
+// appBaseURL is the externally reachable base URL used to build OAuth
+// redirect URIs. It matches the address main.go's app.Listen binds to.
+const appBaseURL = "http://localhost:8080"
+
+// OAuthToken is the token response returned by a provider's token endpoint.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ProviderUser is the account info a provider returns, normalized to the
+// fields we care about. Raw keeps the provider's own payload so it can be
+// stored alongside the linked identity.
+type ProviderUser struct {
+	ID    string
+	Name  string
+	Email string
+	Raw   json.RawMessage
+}
+
+// OAuthProvider is implemented by every supported OAuth2 login provider.
+type OAuthProvider interface {
+	AuthCodeURL(state string) string
+	Exchange(code string) (OAuthToken, error)
+	FetchUserInfo(token OAuthToken) (ProviderUser, error)
+}
+
+// httpOAuthProvider implements OAuthProvider on top of the standard
+// form-encoded authorization-code grant shared by Google and GitHub; only the
+// endpoints and the user-info payload shape differ between them. Lark/Feishu
+// doesn't fit this shape (see larkOAuthProvider) so it isn't built on top of
+// this type.
+type httpOAuthProvider struct {
+	clientID     string
+	clientSecret string
+	authURL      string
+	tokenURL     string
+	userInfoURL  string
+	redirectURL  string
+	scope        string
+	parseUser    func(body []byte, token OAuthToken) (ProviderUser, error)
+}
+
+func (p httpOAuthProvider) AuthCodeURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", p.redirectURL)
+	params.Set("state", state)
+	params.Set("scope", p.scope)
+	params.Set("response_type", "code")
+	return p.authURL + "?" + params.Encode()
+}
+
+func (p httpOAuthProvider) Exchange(code string) (OAuthToken, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "authorization_code")
+	formData.Set("code", code)
+	formData.Set("client_id", p.clientID)
+	formData.Set("client_secret", p.clientSecret)
+	formData.Set("redirect_uri", p.redirectURL)
+
+	req, _ := http.NewRequest(http.MethodPost, p.tokenURL, strings.NewReader(formData.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return OAuthToken{}, fmt.Errorf("failed to get access token: %s", string(body))
+	}
+
+	var token OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return OAuthToken{}, err
+	}
+	return token, nil
+}
+
+func (p httpOAuthProvider) FetchUserInfo(token OAuthToken) (ProviderUser, error) {
+	req, _ := http.NewRequest(http.MethodGet, p.userInfoURL, nil)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ProviderUser{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ProviderUser{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ProviderUser{}, fmt.Errorf("failed to fetch user info: %s", string(body))
+	}
+
+	return p.parseUser(body, token)
+}
+
+// googleUserInfo is the payload returned by Google's userinfo endpoint.
+type googleUserInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Picture string `json:"picture"`
+}
+
+func newGoogleProvider(env *config.Env) OAuthProvider {
+	return httpOAuthProvider{
+		clientID:     env.GOOGLE_CLIENT_ID,
+		clientSecret: env.GOOGLE_CLIENT_SECRET,
+		authURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL:     "https://oauth2.googleapis.com/token",
+		userInfoURL:  "https://www.googleapis.com/oauth2/v1/userinfo",
+		redirectURL:  appBaseURL + "/auth/google/callback",
+		scope:        "openid email profile",
+		parseUser: func(body []byte, _ OAuthToken) (ProviderUser, error) {
+			var info googleUserInfo
+			if err := json.Unmarshal(body, &info); err != nil {
+				return ProviderUser{}, err
+			}
+			return ProviderUser{ID: info.ID, Name: info.Name, Email: info.Email, Raw: body}, nil
+		},
+	}
+}
+
+// githubUserInfo is the payload returned by GitHub's /user endpoint.
+type githubUserInfo struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func newGitHubProvider(env *config.Env) OAuthProvider {
+	return httpOAuthProvider{
+		clientID:     env.GITHUB_CLIENT_ID,
+		clientSecret: env.GITHUB_CLIENT_SECRET,
+		authURL:      "https://github.com/login/oauth/authorize",
+		tokenURL:     "https://github.com/login/oauth/access_token",
+		userInfoURL:  "https://api.github.com/user",
+		redirectURL:  appBaseURL + "/auth/github/callback",
+		scope:        "read:user user:email",
+		parseUser: func(body []byte, _ OAuthToken) (ProviderUser, error) {
+			var info githubUserInfo
+			if err := json.Unmarshal(body, &info); err != nil {
+				return ProviderUser{}, err
+			}
+			name := info.Name
+			if name == "" {
+				name = info.Login
+			}
+			return ProviderUser{ID: strconv.Itoa(info.ID), Name: name, Email: info.Email, Raw: body}, nil
+		},
+	}
+}
+
+// larkUserInfo is the `data` payload returned by Lark's user_info endpoint.
+type larkUserInfo struct {
+	OpenID string `json:"open_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// larkEnvelope is the {code, msg, data} wrapper every Lark open-api response
+// is returned in, success or failure.
+type larkEnvelope[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+// larkOAuthProvider implements Lark/Feishu's actual login flow, which doesn't
+// fit httpOAuthProvider's generic authorization_code grant: the user token
+// exchange takes a JSON body authenticated with an `Authorization: Bearer
+// <app_access_token>` header rather than client_id/client_secret form fields,
+// and that app_access_token has to be fetched in a call of its own first.
+type larkOAuthProvider struct {
+	appID       string
+	appSecret   string
+	authURL     string
+	appTokenURL string
+	tokenURL    string
+	userInfoURL string
+	redirectURL string
+}
+
+func newLarkProvider(env *config.Env) OAuthProvider {
+	return larkOAuthProvider{
+		appID:       env.LARK_APP_ID,
+		appSecret:   env.LARK_APP_SECRET,
+		authURL:     "https://open.larksuite.com/open-apis/authen/v1/index",
+		appTokenURL: "https://open.larksuite.com/open-apis/auth/v3/app_access_token/internal",
+		tokenURL:    "https://open.larksuite.com/open-apis/authen/v1/access_token",
+		userInfoURL: "https://open.larksuite.com/open-apis/authen/v1/user_info",
+		redirectURL: appBaseURL + "/auth/lark/callback",
+	}
+}
+
+func (p larkOAuthProvider) AuthCodeURL(state string) string {
+	params := url.Values{}
+	params.Set("app_id", p.appID)
+	params.Set("redirect_uri", p.redirectURL)
+	params.Set("state", state)
+	return p.authURL + "?" + params.Encode()
+}
+
+// appAccessToken fetches the short-lived app-level token Lark requires to
+// authorize the user token exchange below.
+func (p larkOAuthProvider) appAccessToken() (string, error) {
+	reqBody, err := json.Marshal(struct {
+		AppID     string `json:"app_id"`
+		AppSecret string `json:"app_secret"`
+	}{AppID: p.appID, AppSecret: p.appSecret})
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, p.appTokenURL, bytes.NewReader(reqBody))
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get app access token: %s", string(body))
+	}
+
+	var parsed larkEnvelope[struct {
+		AppAccessToken string `json:"app_access_token"`
+	}]
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+	if parsed.Code != 0 {
+		return "", fmt.Errorf("failed to get app access token: %s", parsed.Msg)
+	}
+	return parsed.Data.AppAccessToken, nil
+}
+
+func (p larkOAuthProvider) Exchange(code string) (OAuthToken, error) {
+	appToken, err := p.appAccessToken()
+	if err != nil {
+		return OAuthToken{}, err
+	}
+
+	reqBody, err := json.Marshal(struct {
+		GrantType string `json:"grant_type"`
+		Code      string `json:"code"`
+	}{GrantType: "authorization_code", Code: code})
+	if err != nil {
+		return OAuthToken{}, err
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, p.tokenURL, bytes.NewReader(reqBody))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+appToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return OAuthToken{}, fmt.Errorf("failed to get access token: %s", string(body))
+	}
+
+	var parsed larkEnvelope[struct {
+		AccessToken string `json:"access_token"`
+	}]
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return OAuthToken{}, err
+	}
+	if parsed.Code != 0 {
+		return OAuthToken{}, fmt.Errorf("failed to get access token: %s", parsed.Msg)
+	}
+
+	return OAuthToken{AccessToken: parsed.Data.AccessToken}, nil
+}
+
+func (p larkOAuthProvider) FetchUserInfo(token OAuthToken) (ProviderUser, error) {
+	req, _ := http.NewRequest(http.MethodGet, p.userInfoURL, nil)
+	req.Header.Add("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ProviderUser{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ProviderUser{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ProviderUser{}, fmt.Errorf("failed to fetch user info: %s", string(body))
+	}
+
+	var parsed larkEnvelope[larkUserInfo]
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return ProviderUser{}, err
+	}
+	if parsed.Code != 0 {
+		return ProviderUser{}, fmt.Errorf("failed to fetch user info: %s", parsed.Msg)
+	}
+
+	return ProviderUser{ID: parsed.Data.OpenID, Name: parsed.Data.Name, Email: parsed.Data.Email, Raw: body}, nil
+}
+
+// NewProviderRegistry builds the name -> OAuthProvider map used by OAuthService.
+func NewProviderRegistry(env *config.Env) map[string]OAuthProvider {
+	return map[string]OAuthProvider{
+		"google": newGoogleProvider(env),
+		"github": newGitHubProvider(env),
+		"lark":   newLarkProvider(env),
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+	"gorm.io/datatypes"
+)
+
+// OAuthService resolves and links users across the registered OAuth
+// providers.
+type OAuthService struct {
+	providers  map[string]OAuthProvider
+	users      repository.UserRepository
+	identities repository.OAuthIdentityRepository
+}
+
+func NewOAuthService(providers map[string]OAuthProvider, users repository.UserRepository, identities repository.OAuthIdentityRepository) *OAuthService {
+	return &OAuthService{providers: providers, users: users, identities: identities}
+}
+
+// Provider looks up a registered provider by name.
+func (s *OAuthService) Provider(name string) (OAuthProvider, bool) {
+	provider, ok := s.providers[name]
+	return provider, ok
+}
+
+// RandomState generates a URL-safe random token for the oauthstate cookie.
+func RandomState() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
+
+// Login exchanges code with provider and resolves the User behind it,
+// linking or creating an OAuthIdentity as needed.
+func (s *OAuthService) Login(providerName, code string) (*models.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", providerName)
+	}
+
+	token, err := provider.Exchange(code)
+	if err != nil {
+		return nil, err
+	}
+
+	providerUser, err := provider.FetchUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrCreateUser(providerName, providerUser)
+}
+
+// Link exchanges code with provider and attaches the resulting identity to
+// an already-authenticated user.
+func (s *OAuthService) Link(providerName string, user *models.User, code string) (*models.OAuthIdentity, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", providerName)
+	}
+
+	token, err := provider.Exchange(code)
+	if err != nil {
+		return nil, err
+	}
+
+	providerUser, err := provider.FetchUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &models.OAuthIdentity{
+		Provider:       providerName,
+		ProviderUserID: providerUser.ID,
+		UserID:         user.ID,
+		Info:           datatypes.JSON(providerUser.Raw),
+	}
+	if err := s.identities.Create(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *OAuthService) findOrCreateUser(providerName string, pu ProviderUser) (*models.User, error) {
+	if identity, err := s.identities.FindByProviderAndProviderUserID(providerName, pu.ID); err == nil {
+		return s.users.FindByID(identity.UserID)
+	}
+
+	// Some providers (GitHub, unless /user/emails is queried) return no email
+	// at all. Matching on an empty email would merge every such login into
+	// whichever blank-email user happened to be created first, so only look
+	// up by email when the provider actually gave us one; otherwise a new
+	// user is created and disambiguated solely by the OAuthIdentity below.
+	var user *models.User
+	if pu.Email != "" {
+		if existing, err := s.users.FindByEmail(pu.Email); err == nil {
+			user = existing
+		}
+	}
+
+	if user == nil {
+		user = &models.User{
+			Name:      pu.Name,
+			Email:     pu.Email,
+			Phone:     0,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Password:  "",
+		}
+		if err := s.users.Create(user); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := &models.OAuthIdentity{
+		Provider:       providerName,
+		ProviderUserID: pu.ID,
+		UserID:         user.ID,
+		Info:           datatypes.JSON(pu.Raw),
+	}
+	if err := s.identities.Create(identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DioSyahrizal/tylf-backend/models"
+)
+
+func TestOAuthService_LoginCreatesThenReusesUser(t *testing.T) {
+	providers := map[string]OAuthProvider{
+		"google": fakeOAuthProvider{providerUserID: "g-1", name: "Jane Doe", email: "jane@example.com"},
+	}
+	users := newFakeUserRepository()
+	identities := newFakeOAuthIdentityRepository()
+	svc := NewOAuthService(providers, users, identities)
+
+	first, err := svc.Login("google", "code-1")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if first.Email != "jane@example.com" {
+		t.Fatalf("Login() email = %q, want %q", first.Email, "jane@example.com")
+	}
+
+	second, err := svc.Login("google", "code-2")
+	if err != nil {
+		t.Fatalf("Login() second call error = %v", err)
+	}
+	if second.ID != first.ID {
+		t.Fatalf("Login() second call resolved a different user: %d != %d", second.ID, first.ID)
+	}
+}
+
+func TestOAuthService_Login_EmptyEmailDoesNotMergeDistinctUsers(t *testing.T) {
+	providers := map[string]OAuthProvider{
+		"github": fakeOAuthProvider{providerUserID: "gh-1", name: "Jane Doe", email: ""},
+	}
+	users := newFakeUserRepository()
+	identities := newFakeOAuthIdentityRepository()
+	svc := NewOAuthService(providers, users, identities)
+
+	first, err := svc.Login("github", "code-1")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	providers["github"] = fakeOAuthProvider{providerUserID: "gh-2", name: "John Roe", email: ""}
+	svc = NewOAuthService(providers, users, identities)
+
+	second, err := svc.Login("github", "code-2")
+	if err != nil {
+		t.Fatalf("Login() second call error = %v", err)
+	}
+
+	if second.ID == first.ID {
+		t.Fatalf("Login() merged two distinct empty-email identities into user %d", first.ID)
+	}
+}
+
+func TestOAuthService_Login_UnknownProvider(t *testing.T) {
+	svc := NewOAuthService(map[string]OAuthProvider{}, newFakeUserRepository(), newFakeOAuthIdentityRepository())
+
+	if _, err := svc.Login("does-not-exist", "code"); err == nil {
+		t.Fatal("Login() with an unknown provider succeeded")
+	}
+}
+
+func TestOAuthService_Link_AttachesIdentityToExistingUser(t *testing.T) {
+	providers := map[string]OAuthProvider{
+		"github": fakeOAuthProvider{providerUserID: "gh-1", name: "Jane Doe", email: "jane@github.example.com"},
+	}
+	users := newFakeUserRepository()
+	identities := newFakeOAuthIdentityRepository()
+	svc := NewOAuthService(providers, users, identities)
+
+	existing := &models.User{Name: "Jane Doe", Email: "jane@example.com", Password: "x", Phone: 81234567890}
+	if err := users.Create(existing); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	identity, err := svc.Link("github", existing, "code-1")
+	if err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if identity.UserID != existing.ID {
+		t.Fatalf("Link() identity.UserID = %d, want %d", identity.UserID, existing.ID)
+	}
+
+	loggedIn, err := svc.Login("github", "code-2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if loggedIn.ID != existing.ID {
+		t.Fatalf("Login() resolved %d, want the linked user %d", loggedIn.ID, existing.ID)
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"github.com/DioSyahrizal/tylf-backend/models"
+	"github.com/DioSyahrizal/tylf-backend/repository"
+)
+
+// UserService holds user lookups that aren't specific to any auth flow.
+type UserService struct {
+	users repository.UserRepository
+}
+
+func NewUserService(users repository.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+func (s *UserService) List() ([]models.User, error) {
+	return s.users.List()
+}
+
+func (s *UserService) FindByID(id int) (*models.User, error) {
+	return s.users.FindByID(id)
+}